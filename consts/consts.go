@@ -0,0 +1,35 @@
+// Package consts holds the small set of shared constants the persistence layer's
+// authentication and password-reset code depends on.
+package consts
+
+import "time"
+
+const (
+	// DefaultEncryptionKey is the fallback key used to derive encryption keys for
+	// deployments that never set an explicit one. Kept stable across releases so
+	// existing installs don't get locked out of previously-encrypted data.
+	DefaultEncryptionKey = "not so secret key"
+
+	// PasswordAutogenPrefix marks passwords the server generated itself (e.g. for the
+	// first admin user), rather than ones a human chose.
+	PasswordAutogenPrefix = "__NAVIDROME_AUTOGEN__"
+
+	// PasswordsEncryptedKey and PasswordsHashedKey are property-table keys for the password
+	// storage scheme. initPasswordEncryptionKey writes PasswordsHashedKey in bcrypt mode
+	// and reads neither; PasswordsEncryptedKey is no longer read or written.
+	PasswordsEncryptedKey = "PasswordsEncrypted"
+	PasswordsHashedKey    = "PasswordsHashed"
+
+	// PasswordsDEKKey is the property-table key under which the wrapped Data Encryption
+	// Key for reversible passwords is stored.
+	PasswordsDEKKey = "PasswordsDEK"
+
+	// AuthMode values. "reversible" keeps the legacy AES-encrypted password storage the
+	// Subsonic token+salt auth flow needs; "bcrypt" is the default otherwise.
+	AuthModeReversible = "reversible"
+	AuthModeBcrypt     = "bcrypt"
+
+	// PasswordResetTokenDuration is how long a self-service password reset token stays
+	// valid after being issued.
+	PasswordResetTokenDuration = 2 * time.Hour
+)
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"context"
+
+	"github.com/navidrome/navidrome/model"
+)
+
+// Login is the single call site responsible for validating a username/password pair,
+// used by every auth flow (web UI, Subsonic plaintext/"p=" login) that isn't the
+// Subsonic token+salt scheme. It always goes through UserRepository.Authenticate, which
+// supports both bcrypt and legacy encrypted passwords, instead of comparing passwords
+// itself: FindByUsernameWithPassword only works in AuthMode "reversible" and must not be
+// used here now that bcrypt is the default.
+func Login(ctx context.Context, ds model.DataStore, username, password string) (*model.User, error) {
+	usr, err := ds.User(ctx).Authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.User(ctx).UpdateLastLoginAt(usr.ID); err != nil {
+		return nil, err
+	}
+	return usr, nil
+}
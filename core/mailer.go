@@ -0,0 +1,23 @@
+package core
+
+import (
+	"context"
+
+	"github.com/navidrome/navidrome/log"
+)
+
+// PasswordResetMailer delivers a password reset token to its owner out-of-band. Callers must
+// treat the token as a secret: anyone holding it can set a new password for the account.
+type PasswordResetMailer interface {
+	SendPasswordReset(ctx context.Context, username, token string) error
+}
+
+// LogMailer just logs the token instead of sending it anywhere. It exists so the reset flow
+// has somewhere to deliver the token without a real mailer configured; it must be replaced
+// with an actual e-mail integration before this feature is exposed on a production instance.
+type LogMailer struct{}
+
+func (LogMailer) SendPasswordReset(ctx context.Context, username, token string) error {
+	log.Info(ctx, "Password reset token issued (no mailer configured)", "user", username, "token", token)
+	return nil
+}
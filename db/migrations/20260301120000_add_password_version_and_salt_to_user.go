@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddPasswordVersionAndSaltToUser, downAddPasswordVersionAndSaltToUser)
+}
+
+func upAddPasswordVersionAndSaltToUser(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	alter table user add column password_version varchar(20) not null default 'encrypted';
+	alter table user add column salt varchar(255) not null default '';
+	`)
+	return err
+}
+
+func downAddPasswordVersionAndSaltToUser(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	alter table user drop column password_version;
+	alter table user drop column salt;
+	`)
+	return err
+}
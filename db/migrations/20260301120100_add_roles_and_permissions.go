@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddRolesAndPermissions, downAddRolesAndPermissions)
+}
+
+func upAddRolesAndPermissions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	create table role (
+		id varchar(255) not null primary key,
+		name varchar(255) not null unique,
+		created_at datetime,
+		updated_at datetime
+	);
+
+	create table role_permission (
+		role_id varchar(255) not null references role(id) on delete cascade,
+		resource varchar(255) not null,
+		action varchar(20) not null,
+		primary key (role_id, resource, action)
+	);
+
+	create table user_role (
+		user_id varchar(255) not null references user(id) on delete cascade,
+		role_id varchar(255) not null references role(id) on delete cascade,
+		primary key (user_id, role_id)
+	);
+	`)
+	return err
+}
+
+func downAddRolesAndPermissions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	drop table user_role;
+	drop table role_permission;
+	drop table role;
+	`)
+	return err
+}
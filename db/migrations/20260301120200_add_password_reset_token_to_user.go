@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddPasswordResetTokenToUser, downAddPasswordResetTokenToUser)
+}
+
+func upAddPasswordResetTokenToUser(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	alter table user add column reset_token_hash varchar(255) not null default '';
+	alter table user add column reset_token_expires_at datetime;
+	`)
+	return err
+}
+
+func downAddPasswordResetTokenToUser(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+	alter table user drop column reset_token_hash;
+	alter table user drop column reset_token_expires_at;
+	`)
+	return err
+}
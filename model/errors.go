@@ -0,0 +1,12 @@
+package model
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a lookup by id/username/etc. matches no row.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidAuth is returned by UserRepository.Authenticate when the supplied
+	// credentials don't match the stored ones.
+	ErrInvalidAuth = errors.New("invalid authentication credentials")
+)
@@ -0,0 +1,9 @@
+// Package id generates the random ids used as primary keys across the persistence layer.
+package id
+
+import "github.com/google/uuid"
+
+// NewRandom returns a new random id, suitable for use as a primary key.
+func NewRandom() string {
+	return uuid.NewString()
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Library is a scanned music folder that users can be granted access to.
+type Library struct {
+	ID                 int       `structs:"id" json:"id"`
+	Name               string    `structs:"name" json:"name"`
+	Path               string    `structs:"path" json:"path"`
+	RemotePath         string    `structs:"remote_path" json:"remotePath"`
+	LastScanAt         time.Time `structs:"last_scan_at" json:"lastScanAt"`
+	LastScanStartedAt  time.Time `structs:"last_scan_started_at" json:"lastScanStartedAt"`
+	FullScanInProgress bool      `structs:"full_scan_in_progress" json:"fullScanInProgress"`
+	DefaultNewUsers    bool      `structs:"default_new_users" json:"defaultNewUsers"`
+	CreatedAt          time.Time `structs:"created_at" json:"createdAt"`
+	UpdatedAt          time.Time `structs:"updated_at" json:"updatedAt"`
+}
+
+type Libraries []Library
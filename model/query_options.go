@@ -0,0 +1,10 @@
+package model
+
+// QueryOptions narrows/orders/paginates a GetAll-style query.
+type QueryOptions struct {
+	Sort    string
+	Order   string
+	Max     int
+	Offset  int
+	Filters map[string]any
+}
@@ -0,0 +1,19 @@
+// Package request holds context helpers for data carried on the incoming HTTP request, such
+// as the logged-in user.
+package request
+
+import (
+	"context"
+
+	"github.com/navidrome/navidrome/model"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// UserFrom returns the logged-in user stored in ctx by the auth middleware, if any.
+func UserFrom(ctx context.Context) (*model.User, bool) {
+	usr, ok := ctx.Value(userContextKey).(*model.User)
+	return usr, ok
+}
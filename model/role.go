@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Permission grants action (e.g. "read"/"write") on resource (e.g. "user") to whoever holds
+// the role it's attached to.
+type Permission struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// Role is a named set of permissions that can be assigned to users. Two roles are seeded on
+// first run: "admin" (implicitly grants everything) and "user" (no permissions of its own).
+type Role struct {
+	ID          string       `structs:"id" json:"id"`
+	Name        string       `structs:"name" json:"name"`
+	Permissions []Permission `structs:"-" json:"permissions,omitempty"`
+	CreatedAt   time.Time    `structs:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time    `structs:"updated_at" json:"updatedAt"`
+}
+
+type Roles []Role
+
+// RoleRepository is the persistence-layer contract for roles and role membership.
+type RoleRepository interface {
+	Get(id string) (*Role, error)
+	GetAll(options ...QueryOptions) (Roles, error)
+	FindByName(name string) (*Role, error)
+	FindByUserID(userID string) (Roles, error)
+	Put(role *Role) error
+	AssignToUser(userID, roleID string) error
+	RevokeFromUser(userID, roleID string) error
+	HasPermission(userID, resource, action string) (bool, error)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// User is a Navidrome account. NewPassword/CurrentPassword are write-only, request-scoped
+// fields: they are never read back from the database (see structs:"-") and are cleared by the
+// persistence layer once consumed.
+type User struct {
+	ID              string    `structs:"id" json:"id"`
+	UserName        string    `structs:"user_name" json:"userName"`
+	Name            string    `structs:"name" json:"name"`
+	Email           string    `structs:"email" json:"email"`
+	IsAdmin         bool      `structs:"is_admin" json:"isAdmin"`
+	Password        string    `structs:"password" json:"-"`
+	NewPassword     string    `structs:"-" json:"password,omitempty"`
+	CurrentPassword string    `structs:"-" json:"currentPassword,omitempty"`
+	LastLoginAt     time.Time `structs:"last_login_at" json:"lastLoginAt"`
+	LastAccessAt    time.Time `structs:"last_access_at" json:"lastAccessAt"`
+	CreatedAt       time.Time `structs:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time `structs:"updated_at" json:"updatedAt"`
+	Libraries       Libraries `structs:"-" json:"libraries,omitempty"`
+}
+
+type Users []User
+
+// UserRepository is the persistence-layer contract for users, covering both the generic
+// lookups used throughout the app and the auth/password-reset operations added alongside
+// bcrypt support.
+type UserRepository interface {
+	CountAll(options ...QueryOptions) (int64, error)
+	Get(id string) (*User, error)
+	GetAll(options ...QueryOptions) (Users, error)
+	Put(u *User) error
+	FindFirstAdmin() (*User, error)
+	FindByUsername(username string) (*User, error)
+	// FindByUsernameWithPassword only works in AuthMode "reversible"; everywhere else,
+	// use Authenticate.
+	FindByUsernameWithPassword(username string) (*User, error)
+	Authenticate(username, password string) (*User, error)
+	RequestPasswordReset(username string) (token string, expiresAt time.Time, err error)
+	ResetPassword(token, newPassword string) error
+	ChangePassword(userID, oldPassword, newPassword string) error
+	AdminResetPassword(userID, newPassword string) error
+	UpdateLastLoginAt(id string) error
+	UpdateLastAccessAt(id string) error
+	GetUserLibraries(userID string) (Libraries, error)
+	SetUserLibraries(userID string, libraryIDs []int) error
+}
+
+// DataStore is the entry point for all repositories. Only the accessors the persistence
+// package and its REST wiring actually use are declared here.
+type DataStore interface {
+	User(ctx context.Context) UserRepository
+	Role(ctx context.Context) RoleRepository
+}
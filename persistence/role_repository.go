@@ -0,0 +1,340 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	. "github.com/Masterminds/squirrel"
+	"github.com/deluan/rest"
+	"github.com/navidrome/navidrome/log"
+	"github.com/navidrome/navidrome/model"
+	"github.com/navidrome/navidrome/model/id"
+	"github.com/navidrome/navidrome/utils/slice"
+	"github.com/pocketbase/dbx"
+)
+
+// Built-in role names, seeded on first run by seedBuiltinRoles so existing installs keep
+// working exactly as before: members of "admin" keep full access, members of "user" keep
+// the ability to edit their own profile (the only permission a regular user had pre-roles).
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Resource/action pairs recognized by hasPermission. Kept small on purpose: only the
+// resources that actually gate behavior in this repo today.
+const (
+	resourceUser = "user"
+	actionRead   = "read"
+	actionWrite  = "write"
+)
+
+type roleRepository struct {
+	sqlRepository
+}
+
+type dbRole struct {
+	*model.Role     `structs:",flatten"`
+	PermissionsJSON string `structs:"-" json:"-"`
+}
+
+func (r *dbRole) PostScan() error {
+	if r.PermissionsJSON != "" {
+		if err := json.Unmarshal([]byte(r.PermissionsJSON), &r.Role.Permissions); err != nil {
+			return fmt.Errorf("parsing role permissions from db: %w", err)
+		}
+	}
+	return nil
+}
+
+type dbRoles []dbRole
+
+func (rs dbRoles) toModels() model.Roles {
+	return slice.Map(rs, func(r dbRole) model.Role { return *r.Role })
+}
+
+var seedRolesOnce sync.Once
+
+func NewRoleRepository(ctx context.Context, db dbx.Builder) model.RoleRepository {
+	r := &roleRepository{}
+	r.ctx = ctx
+	r.db = db
+	r.tableName = "role"
+	r.registerModel(&model.Role{}, nil)
+	seedRolesOnce.Do(func() {
+		if err := r.seedBuiltinRoles(); err != nil {
+			log.Error(ctx, "Could not seed built-in roles", err)
+		}
+	})
+	return r
+}
+
+// seedBuiltinRoles creates the "admin" role if it doesn't exist yet, writes the "user" role
+// (creating it if needed, and resetting its permissions to none on every run), and
+// materializes every user's legacy IsAdmin flag as membership in the "admin" role, so
+// permission checks based on roles produce the exact same result IsAdmin did before.
+func (r *roleRepository) seedBuiltinRoles() error {
+	admin, err := r.FindByName(RoleAdmin)
+	if errors.Is(err, model.ErrNotFound) {
+		admin = &model.Role{Name: RoleAdmin}
+		if err := r.Put(admin); err != nil {
+			return fmt.Errorf("creating built-in admin role: %w", err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// The built-in "user" role intentionally has no permissions of its own: a regular
+	// user's ability to edit (only) their own profile comes from the isSelf checks in
+	// userRepository.Read/Update, not from a resourceUser/actionWrite grant here. Granting
+	// that permission on this role would hand every logged-in user hasPermission-gated
+	// write access to *any* user row (Save/Delete/SetUserLibraries), which is exactly the
+	// admin-only capability roles are meant to scope, not broaden.
+	user := &model.Role{
+		Name: RoleUser,
+	}
+	if existing, err := r.FindByName(RoleUser); err == nil {
+		user.ID = existing.ID
+	} else if !errors.Is(err, model.ErrNotFound) {
+		return err
+	}
+	if err := r.Put(user); err != nil {
+		return fmt.Errorf("creating built-in user role: %w", err)
+	}
+
+	sql := Expr(`INSERT OR IGNORE INTO user_role (user_id, role_id)
+		SELECT id, ? FROM user WHERE is_admin = true`, admin.ID)
+	if _, err := r.executeSQL(sql); err != nil {
+		return fmt.Errorf("assigning admin role to existing admin users: %w", err)
+	}
+	sql = Expr(`INSERT OR IGNORE INTO user_role (user_id, role_id)
+		SELECT id, ? FROM user WHERE is_admin = false`, user.ID)
+	if _, err := r.executeSQL(sql); err != nil {
+		return fmt.Errorf("assigning user role to existing regular users: %w", err)
+	}
+	return nil
+}
+
+func (r *roleRepository) selectRoleWithPermissions(options ...model.QueryOptions) SelectBuilder {
+	return r.newSelect(options...).
+		Columns(`role.*`,
+			`COALESCE(json_group_array(json_object(
+				'resource', role_permission.resource,
+				'action', role_permission.action
+			)) FILTER (WHERE role_permission.resource IS NOT NULL), '[]') AS permissions_json`).
+		LeftJoin("role_permission ON role.id = role_permission.role_id").
+		GroupBy("role.id")
+}
+
+func (r *roleRepository) Get(id string) (*model.Role, error) {
+	sel := r.selectRoleWithPermissions().Where(Eq{"role.id": id})
+	var res dbRole
+	err := r.queryOne(sel, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Role, nil
+}
+
+func (r *roleRepository) GetAll(options ...model.QueryOptions) (model.Roles, error) {
+	sel := r.selectRoleWithPermissions(options...)
+	var res dbRoles
+	err := r.queryAll(sel, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.toModels(), nil
+}
+
+func (r *roleRepository) FindByName(name string) (*model.Role, error) {
+	sel := r.selectRoleWithPermissions().Where(Eq{"role.name": name})
+	var res dbRole
+	err := r.queryOne(sel, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Role, nil
+}
+
+func (r *roleRepository) FindByUserID(userID string) (model.Roles, error) {
+	sel := r.selectRoleWithPermissions().
+		Join("user_role ON user_role.role_id = role.id").
+		Where(Eq{"user_role.user_id": userID})
+	var res dbRoles
+	err := r.queryAll(sel, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.toModels(), nil
+}
+
+func (r *roleRepository) Put(role *model.Role) error {
+	if role.ID == "" {
+		role.ID = id.NewRandom()
+	}
+	values, err := toSQLArgs(*role)
+	if err != nil {
+		return fmt.Errorf("error converting role to SQL args: %w", err)
+	}
+	delete(values, "permissions")
+
+	update := Update(r.tableName).Where(Eq{"id": role.ID}).SetMap(values)
+	count, err := r.executeSQL(update)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		insert := Insert(r.tableName).SetMap(values)
+		if _, err = r.executeSQL(insert); err != nil {
+			return err
+		}
+	}
+	return r.setPermissions(role.ID, role.Permissions)
+}
+
+func (r *roleRepository) setPermissions(roleID string, permissions []model.Permission) error {
+	del := Delete("role_permission").Where(Eq{"role_id": roleID})
+	if _, err := r.executeSQL(del); err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	insert := Insert("role_permission").Columns("role_id", "resource", "action")
+	for _, p := range permissions {
+		insert = insert.Values(roleID, p.Resource, p.Action)
+	}
+	_, err := r.executeSQL(insert)
+	return err
+}
+
+// AssignToUser and RevokeFromUser manage role membership directly, for callers (e.g. the
+// admin/user REST resources) that don't want to go through the generic rest.Repository CRUD.
+
+func (r *roleRepository) AssignToUser(userID, roleID string) error {
+	sql := Expr("INSERT OR IGNORE INTO user_role (user_id, role_id) VALUES (?, ?)", userID, roleID)
+	_, err := r.executeSQL(sql)
+	return err
+}
+
+func (r *roleRepository) RevokeFromUser(userID, roleID string) error {
+	del := Delete("user_role").Where(Eq{"user_id": userID, "role_id": roleID})
+	_, err := r.executeSQL(del)
+	return err
+}
+
+// HasPermission reports whether any role assigned to userID grants action on resource.
+// Membership in the built-in "admin" role always passes, mirroring the legacy IsAdmin check.
+func (r *roleRepository) HasPermission(userID, resource, action string) (bool, error) {
+	roles, err := r.FindByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if role.Name == RoleAdmin {
+			return true, nil
+		}
+		for _, p := range role.Permissions {
+			if p.Resource == resource && p.Action == action {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// REST resource, so the web UI can build a roles management page.
+
+func (r *roleRepository) Count(options ...rest.QueryOptions) (int64, error) {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return 0, rest.ErrPermissionDenied
+	}
+	return r.count(Select(), r.parseRestOptions(r.ctx, options...))
+}
+
+func (r *roleRepository) Read(id string) (any, error) {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return nil, rest.ErrPermissionDenied
+	}
+	role, err := r.Get(id)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, rest.ErrNotFound
+	}
+	return role, err
+}
+
+func (r *roleRepository) ReadAll(options ...rest.QueryOptions) (any, error) {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return nil, rest.ErrPermissionDenied
+	}
+	return r.GetAll(r.parseRestOptions(r.ctx, options...))
+}
+
+func (r *roleRepository) EntityName() string {
+	return "role"
+}
+
+func (r *roleRepository) NewInstance() any {
+	return &model.Role{}
+}
+
+func (r *roleRepository) Save(entity any) (string, error) {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return "", rest.ErrPermissionDenied
+	}
+	role := entity.(*model.Role)
+	if err := r.Put(role); err != nil {
+		return "", err
+	}
+	return role.ID, nil
+}
+
+func (r *roleRepository) Update(id string, entity any, _ ...string) error {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return rest.ErrPermissionDenied
+	}
+	role := entity.(*model.Role)
+	role.ID = id
+	err := r.Put(role)
+	if errors.Is(err, model.ErrNotFound) {
+		return rest.ErrNotFound
+	}
+	return err
+}
+
+func (r *roleRepository) Delete(id string) error {
+	usr := loggedUser(r.ctx)
+	if !usr.IsAdmin {
+		return rest.ErrPermissionDenied
+	}
+	role, err := r.Get(id)
+	if errors.Is(err, model.ErrNotFound) {
+		return rest.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if role.Name == RoleAdmin {
+		return errors.New("the built-in admin role cannot be deleted")
+	}
+	if err := r.setPermissions(id, nil); err != nil {
+		return err
+	}
+	err = r.delete(Eq{"id": id})
+	if errors.Is(err, model.ErrNotFound) {
+		return rest.ErrNotFound
+	}
+	return err
+}
+
+var _ model.RoleRepository = (*roleRepository)(nil)
+var _ rest.Repository = (*roleRepository)(nil)
+var _ rest.Persistable = (*roleRepository)(nil)
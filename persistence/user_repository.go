@@ -2,13 +2,14 @@ package persistence
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	. "github.com/Masterminds/squirrel"
 	"github.com/deluan/rest"
@@ -20,6 +21,18 @@ import (
 	"github.com/navidrome/navidrome/utils"
 	"github.com/navidrome/navidrome/utils/slice"
 	"github.com/pocketbase/dbx"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password storage versions. "encrypted" is the legacy, reversible AES scheme
+// kept only for deployments that rely on the Subsonic token+salt auth flow.
+// "bcrypt" is the default for everyone else, as passwords are never stored
+// in a form that can be recovered.
+const (
+	passwordVersionEncrypted = "encrypted"
+	passwordVersionBcrypt    = "bcrypt"
+
+	bcryptCost = bcrypt.DefaultCost
 )
 
 type userRepository struct {
@@ -27,8 +40,10 @@ type userRepository struct {
 }
 
 type dbUser struct {
-	*model.User   `structs:",flatten"`
-	LibrariesJSON string `structs:"-" json:"-"`
+	*model.User     `structs:",flatten"`
+	LibrariesJSON   string `structs:"-" json:"-"`
+	PasswordVersion string `structs:"-" json:"-"`
+	Salt            string `structs:"-" json:"-"`
 }
 
 func (u *dbUser) PostScan() error {
@@ -115,14 +130,32 @@ func (r *userRepository) Put(u *model.User) error {
 		u.ID = id.NewRandom()
 	}
 	u.UpdatedAt = time.Now()
+	var hashed *hashedPassword
 	if u.NewPassword != "" {
-		_ = r.encryptPassword(u)
+		var err error
+		hashed, err = r.hashPassword(u.NewPassword)
+		if err != nil {
+			return fmt.Errorf("error hashing user's password: %w", err)
+		}
+		u.Password = hashed.hash
+		u.NewPassword = ""
 	}
 	values, err := toSQLArgs(*u)
 	if err != nil {
 		return fmt.Errorf("error converting user to SQL args: %w", err)
 	}
 	delete(values, "current_password")
+	if hashed != nil {
+		values["password_version"] = hashed.version
+		values["salt"] = hashed.salt
+		// A password set through this path (profile edit, admin save, etc.) invalidates
+		// any pending self-service reset token, so it can't be used after the fact.
+		values["reset_token_hash"] = ""
+		values["reset_token_expires_at"] = nil
+	} else {
+		// No new password given: keep the stored one.
+		delete(values, "password")
+	}
 
 	// Save/update the user
 	update := Update(r.tableName).Where(Eq{"id": u.ID}).SetMap(values)
@@ -184,7 +217,14 @@ func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 	return usr.User, nil
 }
 
+// FindByUsernameWithPassword is only usable when the server runs with AuthMode "reversible",
+// as it returns the user with the plaintext password, required by the Subsonic API's
+// token+salt authentication scheme. In "bcrypt" mode (the default), the password cannot be
+// recovered, and callers must validate credentials through Authenticate instead.
 func (r *userRepository) FindByUsernameWithPassword(username string) (*model.User, error) {
+	if conf.Server.AuthMode != consts.AuthModeReversible {
+		return nil, errors.New("cannot retrieve plaintext password, server is configured with AuthMode=bcrypt")
+	}
 	usr, err := r.FindByUsername(username)
 	if err != nil {
 		return nil, err
@@ -193,6 +233,128 @@ func (r *userRepository) FindByUsernameWithPassword(username string) (*model.Use
 	return usr, nil
 }
 
+// Authenticate validates a plaintext password against the user's stored credentials. It
+// supports both password versions: bcrypt hashes are compared directly, while legacy
+// AES-encrypted passwords are decrypted and compared, then, unless AuthMode is "reversible",
+// transparently re-hashed with bcrypt on success, so every successful login gradually
+// migrates the user base.
+func (r *userRepository) Authenticate(username, password string) (*model.User, error) {
+	sel := r.selectUserWithLibraries().Where(Expr("user.user_name = ? COLLATE NOCASE", username))
+	var usr dbUser
+	if err := r.queryOne(sel, &usr); err != nil {
+		return nil, err
+	}
+
+	switch usr.PasswordVersion {
+	case passwordVersionBcrypt:
+		if err := bcrypt.CompareHashAndPassword([]byte(usr.User.Password), []byte(usr.Salt+password)); err != nil {
+			return nil, model.ErrInvalidAuth
+		}
+	default:
+		plain, err := utils.Decrypt(r.ctx, encKey, usr.User.Password)
+		if err != nil || plain != password {
+			return nil, model.ErrInvalidAuth
+		}
+		if conf.Server.AuthMode != consts.AuthModeReversible {
+			if err := r.rehashToBcrypt(usr.User.ID, password); err != nil {
+				log.Error(r.ctx, "Could not migrate user's password to bcrypt", "user", username, err)
+			}
+		}
+	}
+	return usr.User, nil
+}
+
+func (r *userRepository) rehashToBcrypt(userID, password string) error {
+	hashed, err := r.hashBcrypt(password)
+	if err != nil {
+		return err
+	}
+	upd := Update(r.tableName).Where(Eq{"id": userID}).
+		Set("password", hashed.hash).
+		Set("salt", hashed.salt).
+		Set("password_version", hashed.version)
+	_, err = r.executeSQL(upd)
+	return err
+}
+
+// RequestPasswordReset issues a one-time reset token for username, valid for
+// consts.PasswordResetTokenDuration. Only a SHA-256 hash of the token is persisted; the
+// caller is responsible for delivering the returned token to the user out-of-band (e.g. by
+// email) and must treat it as a secret, since anyone holding it can set a new password.
+func (r *userRepository) RequestPasswordReset(username string) (token string, expiresAt time.Time, err error) {
+	usr, err := r.FindByUsername(username)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token = newResetToken()
+	expiresAt = time.Now().Add(consts.PasswordResetTokenDuration)
+	upd := Update(r.tableName).Where(Eq{"id": usr.ID}).
+		Set("reset_token_hash", hashResetToken(token)).
+		Set("reset_token_expires_at", expiresAt)
+	if _, err := r.executeSQL(upd); err != nil {
+		return "", time.Time{}, err
+	}
+	log.Info(r.ctx, "Password reset requested", "user", usr.UserName)
+	return token, expiresAt, nil
+}
+
+// ResetPassword consumes a token issued by RequestPasswordReset and sets newPassword,
+// through the same hashing path as Put. The token is single-use: it is cleared as soon as
+// it is looked up, whether or not the reset ultimately succeeds.
+func (r *userRepository) ResetPassword(token, newPassword string) error {
+	var row struct {
+		ID                  string    `db:"id"`
+		UserName            string    `db:"user_name"`
+		ResetTokenExpiresAt time.Time `db:"reset_token_expires_at"`
+	}
+	sel := Select("id", "user_name", "reset_token_expires_at").From(r.tableName).
+		Where(Eq{"reset_token_hash": hashResetToken(token)})
+	if err := r.queryOne(sel, &row); err != nil {
+		log.Warn(r.ctx, "Password reset attempted with an invalid token")
+		return model.ErrNotFound
+	}
+
+	clear := Update(r.tableName).Where(Eq{"id": row.ID}).
+		Set("reset_token_hash", "").
+		Set("reset_token_expires_at", nil)
+	if _, err := r.executeSQL(clear); err != nil {
+		return err
+	}
+
+	if time.Now().After(row.ResetTokenExpiresAt) {
+		log.Warn(r.ctx, "Password reset attempted with an expired token", "user", row.UserName)
+		return errors.New("password reset token has expired")
+	}
+	if err := validatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	hashed, err := r.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	upd := Update(r.tableName).Where(Eq{"id": row.ID}).
+		Set("password", hashed.hash).
+		Set("salt", hashed.salt).
+		Set("password_version", hashed.version)
+	if _, err := r.executeSQL(upd); err != nil {
+		return err
+	}
+	log.Info(r.ctx, "Password reset completed", "user", row.UserName)
+	return nil
+}
+
+func newResetToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", sum)
+}
+
 func (r *userRepository) UpdateLastLoginAt(id string) error {
 	upd := Update(r.tableName).Where(Eq{"id": id}).Set("last_login_at", time.Now())
 	_, err := r.executeSQL(upd)
@@ -206,9 +368,24 @@ func (r *userRepository) UpdateLastAccessAt(id string) error {
 	return err
 }
 
-func (r *userRepository) Count(options ...rest.QueryOptions) (int64, error) {
+// hasPermission reports whether the logged-in user can perform action on resource, either
+// because they're an admin or because one of their roles grants it (see roleRepository).
+func (r *userRepository) hasPermission(resource, action string) bool {
 	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin {
+	if usr.IsAdmin {
+		return true
+	}
+	roles := NewRoleRepository(r.ctx, r.db)
+	ok, err := roles.HasPermission(usr.ID, resource, action)
+	if err != nil {
+		log.Error(r.ctx, "Error checking user permission", "user", usr.UserName, "resource", resource, "action", action, err)
+		return false
+	}
+	return ok
+}
+
+func (r *userRepository) Count(options ...rest.QueryOptions) (int64, error) {
+	if !r.hasPermission(resourceUser, actionRead) {
 		return 0, rest.ErrPermissionDenied
 	}
 	return r.CountAll(r.parseRestOptions(r.ctx, options...))
@@ -216,7 +393,7 @@ func (r *userRepository) Count(options ...rest.QueryOptions) (int64, error) {
 
 func (r *userRepository) Read(id string) (any, error) {
 	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin && usr.ID != id {
+	if usr.ID != id && !r.hasPermission(resourceUser, actionRead) {
 		return nil, rest.ErrPermissionDenied
 	}
 	usr, err := r.Get(id)
@@ -227,8 +404,7 @@ func (r *userRepository) Read(id string) (any, error) {
 }
 
 func (r *userRepository) ReadAll(options ...rest.QueryOptions) (any, error) {
-	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin {
+	if !r.hasPermission(resourceUser, actionRead) {
 		return nil, rest.ErrPermissionDenied
 	}
 	return r.GetAll(r.parseRestOptions(r.ctx, options...))
@@ -243,8 +419,7 @@ func (r *userRepository) NewInstance() any {
 }
 
 func (r *userRepository) Save(entity any) (string, error) {
-	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin {
+	if !r.hasPermission(resourceUser, actionWrite) {
 		return "", rest.ErrPermissionDenied
 	}
 	u := entity.(*model.User)
@@ -258,14 +433,22 @@ func (r *userRepository) Save(entity any) (string, error) {
 	return u.ID, err
 }
 
+// Update edits a user's profile. It no longer accepts password changes: a request carrying
+// NewPassword or CurrentPassword is rejected outright, closing a class of bugs where
+// password fields silently passed through generic PATCHes. Use ChangePassword (self-service,
+// requires the old password) or AdminResetPassword (admin-only, no old password needed).
 func (r *userRepository) Update(id string, entity any, _ ...string) error {
 	u := entity.(*model.User)
 	u.ID = id
+	if u.NewPassword != "" || u.CurrentPassword != "" {
+		return &rest.ValidationError{Errors: map[string]string{"password": "ra.validation.useChangePasswordEndpoint"}}
+	}
 	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin && usr.ID != u.ID {
+	isSelf := usr.ID == u.ID
+	if !isSelf && !r.hasPermission(resourceUser, actionWrite) {
 		return rest.ErrPermissionDenied
 	}
-	if !usr.IsAdmin {
+	if isSelf && !usr.IsAdmin {
 		if !conf.Server.EnableUserEditing {
 			return rest.ErrPermissionDenied
 		}
@@ -273,13 +456,6 @@ func (r *userRepository) Update(id string, entity any, _ ...string) error {
 		u.UserName = usr.UserName
 	}
 
-	// Decrypt the user's existing password before validating. This is required otherwise the existing password entered by the user will never match.
-	if err := r.decryptPassword(usr); err != nil {
-		return err
-	}
-	if err := validatePasswordChange(u, usr); err != nil {
-		return err
-	}
 	if err := validateUsernameUnique(r, u); err != nil {
 		return err
 	}
@@ -290,32 +466,112 @@ func (r *userRepository) Update(id string, entity any, _ ...string) error {
 	return err
 }
 
-func validatePasswordChange(newUser *model.User, logged *model.User) error {
-	err := &rest.ValidationError{Errors: map[string]string{}}
-	if logged.IsAdmin && newUser.ID != logged.ID {
-		return nil
+// ChangePassword lets a user set a new password for themselves, always requiring their
+// current password regardless of PasswordAutogenPrefix (that shortcut only ever applied to
+// the generic Update path, which no longer accepts password changes at all).
+func (r *userRepository) ChangePassword(userID, oldPassword, newPassword string) error {
+	usr, err := r.Get(userID)
+	if err != nil {
+		return err
 	}
-	if newUser.NewPassword == "" {
-		if newUser.CurrentPassword == "" {
-			return nil
-		}
-		err.Errors["password"] = "ra.validation.required"
+	if !r.verifyPassword(usr, oldPassword) {
+		log.Warn(r.ctx, "Password change rejected: current password did not match", "user", usr.UserName)
+		return &rest.ValidationError{Errors: map[string]string{"currentPassword": "ra.validation.passwordDoesNotMatch"}}
+	}
+	if err := validatePasswordStrength(newPassword); err != nil {
+		return err
 	}
+	return r.setPassword(userID, newPassword)
+}
 
-	if !strings.HasPrefix(logged.Password, consts.PasswordAutogenPrefix) {
-		if newUser.CurrentPassword == "" {
-			err.Errors["currentPassword"] = "ra.validation.required"
-		}
-		if newUser.CurrentPassword != logged.Password {
-			err.Errors["currentPassword"] = "ra.validation.passwordDoesNotMatch"
-		}
+// AdminResetPassword sets a new password for userID without verifying the old one. Like
+// Save/Delete/SetUserLibraries, access is enforced here via hasPermission rather than relying
+// on the REST layer alone; every call is also logged, since it bypasses the usual
+// proof-of-ownership check.
+func (r *userRepository) AdminResetPassword(userID, newPassword string) error {
+	if !r.hasPermission(resourceUser, actionWrite) {
+		return rest.ErrPermissionDenied
+	}
+	if err := validatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+	if err := r.setPassword(userID, newPassword); err != nil {
+		return err
 	}
-	if len(err.Errors) > 0 {
+	log.Info(r.ctx, "Password reset by administrator", "admin", loggedUser(r.ctx).UserName, "user", userID)
+	return nil
+}
+
+// setPassword hashes and stores newPassword for userID, invalidating any pending
+// self-service reset token the same way Put does.
+func (r *userRepository) setPassword(userID, newPassword string) error {
+	hashed, err := r.hashPassword(newPassword)
+	if err != nil {
 		return err
 	}
+	upd := Update(r.tableName).Where(Eq{"id": userID}).
+		Set("password", hashed.hash).
+		Set("salt", hashed.salt).
+		Set("password_version", hashed.version).
+		Set("reset_token_hash", "").
+		Set("reset_token_expires_at", nil)
+	_, err = r.executeSQL(upd)
+	return err
+}
+
+// verifyPassword checks candidate against usr's stored password, picking the comparison
+// strategy (bcrypt compare or legacy decrypt) from the stored password_version.
+func (r *userRepository) verifyPassword(usr *model.User, candidate string) bool {
+	version, salt := r.passwordMeta(usr.ID)
+	if version == passwordVersionBcrypt {
+		return bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(salt+candidate)) == nil
+	}
+	plain, err := utils.Decrypt(r.ctx, encKey, usr.Password)
+	return err == nil && plain == candidate
+}
+
+// validatePasswordStrength enforces the configurable password policy (minimum length and
+// character-class mix) against newPassword. Failed ChangePassword/AdminResetPassword
+// attempts are not rate-limited, neither here nor by the nativeapi routes.
+func validatePasswordStrength(password string) error {
+	policy := conf.Server.PasswordPolicy
+	if len(password) < policy.MinLength {
+		return &rest.ValidationError{Errors: map[string]string{"password": "ra.validation.passwordTooShort"}}
+	}
+	var hasUpper, hasLower, hasDigit bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		}
+	}
+	if policy.RequireMixedCase && !(hasUpper && hasLower) {
+		return &rest.ValidationError{Errors: map[string]string{"password": "ra.validation.passwordRequiresMixedCase"}}
+	}
+	if policy.RequireDigit && !hasDigit {
+		return &rest.ValidationError{Errors: map[string]string{"password": "ra.validation.passwordRequiresDigit"}}
+	}
 	return nil
 }
 
+// passwordMeta returns the stored password_version and salt for a user, looked up directly
+// since neither is exposed on model.User.
+func (r *userRepository) passwordMeta(userID string) (version string, salt string) {
+	var meta struct {
+		PasswordVersion string `db:"password_version"`
+		Salt            string `db:"salt"`
+	}
+	sel := Select("password_version", "salt").From(r.tableName).Where(Eq{"id": userID})
+	if err := r.queryOne(sel, &meta); err != nil {
+		return "", ""
+	}
+	return meta.PasswordVersion, meta.Salt
+}
+
 func validateUsernameUnique(r model.UserRepository, u *model.User) error {
 	usr, err := r.FindByUsername(u.UserName)
 	if errors.Is(err, model.ErrNotFound) {
@@ -331,8 +587,7 @@ func validateUsernameUnique(r model.UserRepository, u *model.User) error {
 }
 
 func (r *userRepository) Delete(id string) error {
-	usr := loggedUser(r.ctx)
-	if !usr.IsAdmin {
+	if !r.hasPermission(resourceUser, actionWrite) {
 		return rest.ErrPermissionDenied
 	}
 	err := r.delete(Eq{"id": id})
@@ -347,73 +602,123 @@ func keyTo32Bytes(input string) []byte {
 	return data[0:]
 }
 
+// initPasswordEncryptionKey is idempotent across restarts. In bcrypt mode it records that
+// state in the PasswordsHashedKey property and sets the key used to decrypt not-yet-migrated
+// legacy passwords. In reversible mode it loads the password DEK: unwrapping the one
+// persisted in PasswordsDEKKey, or, on first run, deriving it from PasswordEncryptionKey
+// (DefaultEncryptionKey when that is unset) and persisting it wrapped by the configured
+// KeyProvider.
 func (r *userRepository) initPasswordEncryptionKey() error {
 	encKey = keyTo32Bytes(consts.DefaultEncryptionKey)
-	if conf.Server.PasswordEncryptionKey == "" {
+	props := NewPropertyRepository(r.ctx, r.db)
+
+	if conf.Server.AuthMode != consts.AuthModeReversible {
+		// Nothing to precompute: new/changed passwords are bcrypt-hashed as they are
+		// written, and legacy encrypted passwords are migrated lazily, on successful
+		// login (see Authenticate), so they must be decrypted with the key they were
+		// encrypted with.
+		if conf.Server.PasswordEncryptionKey != "" {
+			encKey = keyTo32Bytes(conf.Server.PasswordEncryptionKey)
+		}
+		_ = props.Put(consts.PasswordsHashedKey, passwordVersionBcrypt)
 		return nil
 	}
 
-	key := keyTo32Bytes(conf.Server.PasswordEncryptionKey)
-	keySum := fmt.Sprintf("%x", sha256.Sum256(key))
-
-	props := NewPropertyRepository(r.ctx, r.db)
-	savedKeySum, err := props.Get(consts.PasswordsEncryptedKey)
+	// Envelope encryption: the DEK that actually encrypts passwords is generated once and
+	// never changes; only the small wrapped-DEK blob stored in the property table needs to
+	// be re-wrapped when the KEK rotates, so user rows never need to be touched again.
+	provider, err := r.keyProvider()
+	if err != nil {
+		log.Error("Could not initialize password encryption key provider", err)
+		return err
+	}
 
-	// If passwords are already encrypted
+	wrappedDEK, err := props.Get(consts.PasswordsDEKKey)
 	if err == nil {
-		if savedKeySum != keySum {
-			log.Error("Password Encryption Key changed! Users won't be able to login!")
-			return errors.New("passwordEncryptionKey changed")
+		dek, err := provider.Unwrap([]byte(wrappedDEK))
+		if err != nil {
+			log.Error("Could not unwrap password encryption key. The Key Encryption Key may have changed", err)
+			return fmt.Errorf("unwrapping password DEK: %w", err)
 		}
-		encKey = key
+		encKey = dek
 		return nil
 	}
 
-	// if not, try to re-encrypt all current passwords with new encryption key,
-	// assuming they were encrypted with the DefaultEncryptionKey
-	sql := r.newSelect().Columns("id", "user_name", "password")
-	users := model.Users{}
-	err = r.queryAll(sql, &users)
+	// First run: derive the DEK from PasswordEncryptionKey when set, so installs that
+	// already had passwords encrypted before envelope encryption existed keep working
+	// unchanged (the pre-envelope code derived the exact same key from that same string).
+	// Otherwise, fall back to the same DefaultEncryptionKey that the legacy encryption has
+	// always used for installs that never set PasswordEncryptionKey — generating a fresh random DEK
+	// here instead would silently re-encrypt under a key that doesn't match any existing
+	// password, locking out every user on upgrade.
+	var dek []byte
+	if conf.Server.PasswordEncryptionKey != "" {
+		dek = keyTo32Bytes(conf.Server.PasswordEncryptionKey)
+	} else {
+		dek = keyTo32Bytes(consts.DefaultEncryptionKey)
+	}
+	wrapped, err := provider.Wrap(dek)
 	if err != nil {
-		log.Error("Could not encrypt all passwords", err)
+		log.Error("Could not wrap password encryption key", err)
 		return err
 	}
-	log.Warn("New PasswordEncryptionKey set. Encrypting all passwords", "numUsers", len(users))
-	if err = r.decryptAllPasswords(users); err != nil {
+	if err := props.Put(consts.PasswordsDEKKey, string(wrapped)); err != nil {
+		log.Error("Could not persist wrapped password encryption key. It will cause login errors", err)
 		return err
 	}
-	encKey = key
-	for i := range users {
-		u := users[i]
-		u.NewPassword = u.Password
-		if err := r.encryptPassword(&u); err == nil {
-			upd := Update(r.tableName).Set("password", u.NewPassword).Where(Eq{"id": u.ID})
-			_, err = r.executeSQL(upd)
-			if err != nil {
-				log.Error("Password NOT encrypted! This may cause problems!", "user", u.UserName, "id", u.ID, err)
-			} else {
-				log.Warn("Password encrypted successfully", "user", u.UserName, "id", u.ID)
-			}
-		}
+	encKey = dek
+	return nil
+}
+
+// keyProvider builds the KeyProvider used to wrap/unwrap the password DEK, selected from
+// config: a Tink keyset file when PasswordKeysetFile is set, otherwise a StaticKeyProvider
+// seeded from PasswordEncryptionKEK (or, lacking that, consts.DefaultEncryptionKey).
+func (r *userRepository) keyProvider() (KeyProvider, error) {
+	if conf.Server.PasswordKeysetFile != "" {
+		return NewFileKeysetProvider(conf.Server.PasswordKeysetFile)
+	}
+	kek := conf.Server.PasswordEncryptionKEK
+	if kek == "" {
+		kek = consts.DefaultEncryptionKey
 	}
+	return NewStaticKeyProvider(r.ctx, kek), nil
+}
 
-	err = props.Put(consts.PasswordsEncryptedKey, keySum)
-	if err != nil {
-		log.Error("Could not flag passwords as encrypted. It will cause login errors", err)
-		return err
+type hashedPassword struct {
+	hash    string
+	version string
+	salt    string
+}
+
+// hashPassword turns a plaintext password into the value stored in the "password" column,
+// picking the scheme from AuthMode: bcrypt (the default, one-way) or the legacy reversible
+// AES encryption kept only for the Subsonic API's token+salt login flow.
+func (r *userRepository) hashPassword(plainPassword string) (*hashedPassword, error) {
+	if conf.Server.AuthMode == consts.AuthModeReversible {
+		encPassword, err := utils.Encrypt(r.ctx, encKey, plainPassword)
+		if err != nil {
+			log.Error(r.ctx, "Error encrypting user's password", err)
+			return nil, err
+		}
+		return &hashedPassword{hash: encPassword, version: passwordVersionEncrypted}, nil
 	}
-	return nil
+	return r.hashBcrypt(plainPassword)
 }
 
-// encrypts u.NewPassword
-func (r *userRepository) encryptPassword(u *model.User) error {
-	encPassword, err := utils.Encrypt(r.ctx, encKey, u.NewPassword)
+func (r *userRepository) hashBcrypt(plainPassword string) (*hashedPassword, error) {
+	salt := newSalt()
+	hash, err := bcrypt.GenerateFromPassword([]byte(salt+plainPassword), bcryptCost)
 	if err != nil {
-		log.Error(r.ctx, "Error encrypting user's password", "user", u.UserName, err)
-		return err
+		log.Error(r.ctx, "Error hashing user's password", err)
+		return nil, err
 	}
-	u.NewPassword = encPassword
-	return nil
+	return &hashedPassword{hash: string(hash), version: passwordVersionBcrypt, salt: salt}, nil
+}
+
+func newSalt() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x", b)
 }
 
 // decrypts u.Password
@@ -427,15 +732,6 @@ func (r *userRepository) decryptPassword(u *model.User) error {
 	return nil
 }
 
-func (r *userRepository) decryptAllPasswords(users model.Users) error {
-	for i := range users {
-		if err := r.decryptPassword(&users[i]); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Library association methods
 
 func (r *userRepository) GetUserLibraries(userID string) (model.Libraries, error) {
@@ -451,6 +747,9 @@ func (r *userRepository) GetUserLibraries(userID string) (model.Libraries, error
 }
 
 func (r *userRepository) SetUserLibraries(userID string, libraryIDs []int) error {
+	if loggedUser(r.ctx).ID != userID && !r.hasPermission(resourceUser, actionWrite) {
+		return rest.ErrPermissionDenied
+	}
 	// Remove existing associations
 	delSql := Delete("user_library").Where(Eq{"user_id": userID})
 	if _, err := r.executeSQL(delSql); err != nil {
@@ -0,0 +1,70 @@
+package nativeapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/deluan/rest"
+	"github.com/go-chi/chi/v5"
+	"github.com/navidrome/navidrome/model"
+	"github.com/navidrome/navidrome/model/request"
+)
+
+// addChangePasswordRoutes wires the two password-mutation paths that the generic user REST
+// resource no longer accepts (see userRepository.Update): self-service change, which requires
+// the caller's current password, and admin reset, which does not.
+func addChangePasswordRoutes(r chi.Router, ds model.DataStore) {
+	r.Post("/user/{id}/changePassword", changePassword(ds))
+	r.Post("/user/{id}/resetPassword", adminResetPassword(ds))
+}
+
+func changePassword(ds model.DataStore) http.HandlerFunc {
+	type body struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		var b body
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Self-service only: an admin changing someone else's password doesn't have (or need)
+		// the old password, so that case goes through adminResetPassword instead.
+		if user, ok := request.UserFrom(r.Context()); !ok || user.ID != id {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		err := ds.User(r.Context()).ChangePassword(id, b.CurrentPassword, b.NewPassword)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func adminResetPassword(ds model.DataStore) http.HandlerFunc {
+	type body struct {
+		NewPassword string `json:"newPassword"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		var b body
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err := ds.User(r.Context()).AdminResetPassword(id, b.NewPassword)
+		if err == rest.ErrPermissionDenied {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,25 @@
+// Package nativeapi exposes Navidrome's own REST API (as opposed to the Subsonic-compatible
+// one), built from the rest.Repository implementations in the persistence package.
+package nativeapi
+
+import (
+	"net/http"
+
+	"github.com/deluan/rest"
+	"github.com/go-chi/chi/v5"
+	"github.com/navidrome/navidrome/model"
+	"github.com/navidrome/navidrome/persistence"
+)
+
+// Router builds the native REST API router.
+func Router(ds model.DataStore) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/role", func(r chi.Router) {
+		r.Mount("/", rest.NewRouter(func(req *http.Request) rest.Repository {
+			return persistence.NewRoleRepository(req.Context(), persistence.Db())
+		}))
+	})
+	addPasswordResetRoutes(r, ds)
+	addChangePasswordRoutes(r, ds)
+	return r
+}
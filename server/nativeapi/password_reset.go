@@ -0,0 +1,70 @@
+package nativeapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/navidrome/navidrome/core"
+	"github.com/navidrome/navidrome/log"
+	"github.com/navidrome/navidrome/model"
+)
+
+// addPasswordResetRoutes wires the self-service password reset flow added in
+// persistence.UserRepository. Both endpoints are unauthenticated by design: a user who can't
+// log in has no session to authenticate with, so the security boundary is the reset token
+// itself, not the usual auth middleware.
+func addPasswordResetRoutes(r chi.Router, ds model.DataStore) {
+	r.Post("/password/reset-request", requestPasswordReset(ds))
+	r.Post("/password/reset", resetPassword(ds))
+}
+
+func requestPasswordReset(ds model.DataStore) http.HandlerFunc {
+	mailer := core.LogMailer{}
+	type request struct {
+		Username string `json:"username"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		token, _, err := ds.User(r.Context()).RequestPasswordReset(req.Username)
+		if err != nil {
+			// Do not leak whether the username exists: log the real reason, respond as if
+			// the request succeeded either way.
+			log.Warn(r.Context(), "Password reset request failed", "username", req.Username, err)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if err := mailer.SendPasswordReset(r.Context(), req.Username, token); err != nil {
+			log.Error(r.Context(), "Could not deliver password reset token", "username", req.Username, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func resetPassword(ds model.DataStore) http.HandlerFunc {
+	type request struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"newPassword"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err := ds.User(r.Context()).ResetPassword(req.Token, req.NewPassword)
+		if err != nil {
+			if err == model.ErrNotFound {
+				http.Error(w, "invalid or expired token", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}